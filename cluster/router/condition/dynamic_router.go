@@ -0,0 +1,184 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package condition
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"github.com/apache/dubbo-go/cluster/router"
+	"github.com/apache/dubbo-go/common"
+	"github.com/apache/dubbo-go/common/config_center"
+	"github.com/apache/dubbo-go/common/constant"
+	"github.com/apache/dubbo-go/common/extension"
+	"github.com/apache/dubbo-go/common/logger"
+	"github.com/apache/dubbo-go/protocol"
+)
+
+// conditionRouterRuleSuffix is appended to both the application key and the
+// service key to get the config center keys a DynamicRouter subscribes to.
+const conditionRouterRuleSuffix = ".condition-router"
+
+func init() {
+	extension.SetRouterFactory(constant.DynamicConditionRouterName, newDynamicRouterFactory)
+}
+
+// DynamicRouter is a condition router whose rule set is not fixed at
+// construction time. It subscribes to the config center for both an
+// application-scoped key ("{app}.condition-router") and a service-scoped key
+// ("{group}/{interface}:{version}.condition-router"), and hot-swaps the
+// ConditionRouters it runs whenever either changes, without restarting the
+// process.
+type DynamicRouter struct {
+	mu             sync.RWMutex
+	url            *common.URL
+	dynamicConfig  config_center.DynamicConfiguration
+	appKey         string
+	serviceKey     string
+	appRouters     []*ConditionRouter
+	serviceRouters []*ConditionRouter
+}
+
+type dynamicRouterFactory struct{}
+
+func newDynamicRouterFactory() router.PriorityRouterFactory {
+	return &dynamicRouterFactory{}
+}
+
+// NewPriorityRouter implements router.PriorityRouterFactory.
+func (f *dynamicRouterFactory) NewPriorityRouter(url *common.URL) (router.PriorityRouter, error) {
+	return NewDynamicRouter(url)
+}
+
+// NewDynamicRouter builds a DynamicRouter for url against the default
+// config center.
+func NewDynamicRouter(url *common.URL) (*DynamicRouter, error) {
+	dc, err := extension.GetDefaultConfigCenter()
+	if err != nil {
+		return nil, perrors.WithMessage(err, "get default config center")
+	}
+	return NewDynamicRouterWithConfig(url, dc)
+}
+
+// NewDynamicRouterWithConfig is NewDynamicRouter with an explicit
+// config_center.DynamicConfiguration, so callers (and tests) can drive it
+// against something other than the process-wide default.
+func NewDynamicRouterWithConfig(url *common.URL, dc config_center.DynamicConfiguration) (*DynamicRouter, error) {
+	if url == nil {
+		return nil, perrors.Errorf("illegal route URL: nil")
+	}
+
+	d := &DynamicRouter{
+		url:           url,
+		dynamicConfig: dc,
+		appKey:        url.GetParam(constant.ApplicationKey, "") + conditionRouterRuleSuffix,
+		serviceKey:    url.ServiceKey() + conditionRouterRuleSuffix,
+	}
+
+	if err := dc.AddListener(d.appKey, d); err != nil {
+		return nil, perrors.WithMessagef(err, "listen application condition-router key %s", d.appKey)
+	}
+	if err := dc.AddListener(d.serviceKey, d); err != nil {
+		return nil, perrors.WithMessagef(err, "listen service condition-router key %s", d.serviceKey)
+	}
+
+	if rule, err := dc.GetRule(d.appKey); err == nil {
+		d.updateRouters(d.appKey, rule)
+	}
+	if rule, err := dc.GetRule(d.serviceKey); err == nil {
+		d.updateRouters(d.serviceKey, rule)
+	}
+
+	return d, nil
+}
+
+// Process implements config_center.ConfigurationListener. It is invoked by
+// the config center whenever d.appKey or d.serviceKey changes, and
+// hot-swaps the matching rule set.
+func (d *DynamicRouter) Process(event *config_center.ConfigChangeEvent) {
+	value, _ := event.Value.(string)
+	d.updateRouters(event.Key, value)
+}
+
+// updateRouters rebuilds the ConditionRouters for whichever of d.appKey /
+// d.serviceKey rawRule belongs to. An empty rawRule or a document with
+// enabled: false clears that key's rule set to a no-op; a parse failure is
+// logged and the previously installed rule set is left in place rather than
+// swapped out for nothing.
+func (d *DynamicRouter) updateRouters(key, rawRule string) {
+	var routers []*ConditionRouter
+	if len(strings.TrimSpace(rawRule)) > 0 {
+		rule, err := parseRouterRule(rawRule)
+		if err != nil {
+			logger.Errorf("condition router: parse rule for key %s failed: %v", key, err)
+			return
+		}
+		routers, err = rule.toConditionRouters()
+		if err != nil {
+			logger.Errorf("condition router: build routers for key %s failed: %v", key, err)
+			return
+		}
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	switch key {
+	case d.appKey:
+		d.appRouters = routers
+	case d.serviceKey:
+		d.serviceRouters = routers
+	}
+}
+
+// Route implements router.Router: it applies every active sub-router in
+// ascending priority order; among equal priorities, application-scoped rules
+// run before service-scoped ones.
+func (d *DynamicRouter) Route(invokers []protocol.Invoker, url *common.URL, invocation protocol.Invocation) []protocol.Invoker {
+	d.mu.RLock()
+	routers := make([]*ConditionRouter, 0, len(d.appRouters)+len(d.serviceRouters))
+	routers = append(routers, d.appRouters...)
+	routers = append(routers, d.serviceRouters...)
+	d.mu.RUnlock()
+
+	sort.SliceStable(routers, func(i, j int) bool {
+		return routers[i].Priority() < routers[j].Priority()
+	})
+
+	for _, r := range routers {
+		invokers = r.Route(invokers, url, invocation)
+	}
+	return invokers
+}
+
+// URL implements router.Router.
+func (d *DynamicRouter) URL() common.URL {
+	return *d.url
+}
+
+// Priority implements router.Router. A DynamicRouter has no priority of its
+// own: Route already applies its member ConditionRouters in priority order.
+func (d *DynamicRouter) Priority() int64 {
+	return 0
+}
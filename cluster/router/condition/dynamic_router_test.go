@@ -0,0 +1,112 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package condition
+
+import (
+	"fmt"
+	"testing"
+)
+
+import (
+	"github.com/dubbogo/gost/net"
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"github.com/apache/dubbo-go/common"
+	"github.com/apache/dubbo-go/common/config_center"
+	"github.com/apache/dubbo-go/protocol"
+	"github.com/apache/dubbo-go/protocol/invocation"
+)
+
+// fakeDynamicConfiguration is a minimal config_center.DynamicConfiguration
+// that only implements what DynamicRouter needs, so the rest of its methods
+// are left to the embedded nil interface and must not be exercised here.
+type fakeDynamicConfiguration struct {
+	config_center.DynamicConfiguration
+
+	rules     map[string]string
+	listeners map[string]config_center.ConfigurationListener
+}
+
+func newFakeDynamicConfiguration() *fakeDynamicConfiguration {
+	return &fakeDynamicConfiguration{
+		rules:     make(map[string]string),
+		listeners: make(map[string]config_center.ConfigurationListener),
+	}
+}
+
+func (f *fakeDynamicConfiguration) AddListener(key string, listener config_center.ConfigurationListener, _ ...config_center.Option) error {
+	f.listeners[key] = listener
+	return nil
+}
+
+func (f *fakeDynamicConfiguration) GetRule(key string, _ ...config_center.Option) (string, error) {
+	return f.rules[key], nil
+}
+
+// push simulates the config center notifying the listener that key changed.
+func (f *fakeDynamicConfiguration) push(key, rule string) {
+	f.rules[key] = rule
+	if listener, ok := f.listeners[key]; ok {
+		listener.Process(&config_center.ConfigChangeEvent{Key: key, Value: rule})
+	}
+}
+
+func TestDynamicRouterSubscribesBothKeys(t *testing.T) {
+	dc := newFakeDynamicConfiguration()
+	url, _ := common.NewURL("consumer://1.1.1.1/com.foo.BarService?application=bar-consumer&group=&version=")
+	d, err := NewDynamicRouterWithConfig(&url, dc)
+	assert.NoError(t, err)
+	assert.Contains(t, dc.listeners, d.appKey)
+	assert.Contains(t, dc.listeners, d.serviceKey)
+}
+
+func TestDynamicRouterHotSwap(t *testing.T) {
+	dc := newFakeDynamicConfiguration()
+	localIP, _ := gxnet.GetLocalIP()
+	curl, _ := common.NewURL("consumer://" + localIP + "/com.foo.BarService?application=bar-consumer")
+	d, err := NewDynamicRouterWithConfig(&curl, dc)
+	assert.NoError(t, err)
+
+	url1, _ := common.NewURL("dubbo://10.20.3.3:20880/com.foo.BarService")
+	url2, _ := common.NewURL(fmt.Sprintf("dubbo://%s:20880/com.foo.BarService", localIP))
+	invokers := []protocol.Invoker{NewMockInvoker(url1, 1), NewMockInvoker(url2, 2)}
+	inv := &invocation.RPCInvocation{}
+
+	// before any rule is pushed, the router is a no-op
+	assert.Equal(t, invokers, d.Route(invokers, &curl, inv))
+
+	rule := "scope: application\n" +
+		"force: true\n" +
+		"enabled: true\n" +
+		"priority: 1\n" +
+		"conditions:\n" +
+		"  - \"host = " + localIP + " => host = 10.20.3.254\"\n"
+	dc.push(d.appKey, rule)
+
+	// the then-clause names a host neither invoker has, and force is set,
+	// so the rule hard-isolates: no invoker survives rather than falling
+	// back to the unfiltered list.
+	filtered := d.Route(invokers, &curl, inv)
+	assert.Equal(t, 0, len(filtered))
+
+	// disabling the rule hot-swaps back to a no-op, with no restart
+	dc.push(d.appKey, "scope: application\nenabled: false\nconditions: []\n")
+	assert.Equal(t, invokers, d.Route(invokers, &curl, inv))
+}
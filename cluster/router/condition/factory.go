@@ -0,0 +1,62 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package condition
+
+import (
+	"github.com/apache/dubbo-go/cluster/router"
+	"github.com/apache/dubbo-go/common"
+	"github.com/apache/dubbo-go/common/constant"
+	"github.com/apache/dubbo-go/common/extension"
+
+	_ "github.com/apache/dubbo-go/cluster/router/condition/matcher"
+	_ "github.com/apache/dubbo-go/cluster/router/condition/matcher/pattern_value"
+)
+
+func init() {
+	extension.SetRouterFactory(constant.ConditionRouterName, newConditionRouterFactory)
+	extension.SetRouterFactory(constant.ConditionAppRouterName, newAppRouterFactory)
+}
+
+// conditionRouterFactory builds a ConditionRouter from the "rule" param of
+// the url it is given. The router does not check which interface a request
+// is for.
+type conditionRouterFactory struct{}
+
+func newConditionRouterFactory() router.PriorityRouterFactory {
+	return &conditionRouterFactory{}
+}
+
+// NewPriorityRouter implements router.PriorityRouterFactory.
+func (f *conditionRouterFactory) NewPriorityRouter(url *common.URL) (router.PriorityRouter, error) {
+	return NewConditionRouter(url)
+}
+
+// appRouterFactory is registered under the application condition router
+// name. It builds its router exactly as conditionRouterFactory does, from the
+// "rule" param of the url it is given; it does not key the rule by
+// application.
+type appRouterFactory struct{}
+
+func newAppRouterFactory() router.PriorityRouterFactory {
+	return &appRouterFactory{}
+}
+
+// NewPriorityRouter implements router.PriorityRouterFactory.
+func (f *appRouterFactory) NewPriorityRouter(url *common.URL) (router.PriorityRouter, error) {
+	return NewConditionRouter(url)
+}
@@ -368,3 +368,79 @@ func TestNewAppRouterFactory(t *testing.T) {
 	factory := newAppRouterFactory()
 	assert.NotNil(t, factory)
 }
+
+func TestRouteMatchAttachment(t *testing.T) {
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithAttachments(map[string]interface{}{"tag": "canary"}))
+	rule := base64.URLEncoding.EncodeToString([]byte("attachments.tag = canary => host = 1.2.3.4"))
+	router, _ := newConditionRouterFactory().NewPriorityRouter(getRouteUrl(rule))
+	cUrl, _ := common.NewURL("consumer://1.1.1.1/com.foo.BarService")
+	assert.Equal(t, true, router.(*ConditionRouter).MatchWhen(&cUrl, inv))
+
+	rule2 := base64.URLEncoding.EncodeToString([]byte("attachments.tag = stable => host = 1.2.3.4"))
+	router2, _ := newConditionRouterFactory().NewPriorityRouter(getRouteUrl(rule2))
+	assert.Equal(t, false, router2.(*ConditionRouter).MatchWhen(&cUrl, inv))
+}
+
+func TestRouteAttachmentFilter(t *testing.T) {
+	localIP, _ := gxnet.GetLocalIP()
+	url1, _ := common.NewURL("dubbo://10.20.3.3:20880/com.foo.BarService")
+	url2, _ := common.NewURL(fmt.Sprintf("dubbo://%s:20880/com.foo.BarService", localIP))
+	url3, _ := common.NewURL(fmt.Sprintf("dubbo://%s:20880/com.foo.BarService", localIP))
+	invokers := []protocol.Invoker{NewMockInvoker(url1, 1), NewMockInvoker(url2, 2), NewMockInvoker(url3, 3)}
+	cUrl, _ := common.NewURL("consumer://" + localIP + "/com.foo.BarService")
+
+	canary := invocation.NewRPCInvocationWithOptions(invocation.WithAttachments(map[string]interface{}{"tag": "canary"}))
+	rule := base64.URLEncoding.EncodeToString([]byte("attachments.tag = canary => host = " + localIP))
+	router, _ := newConditionRouterFactory().NewPriorityRouter(getRouteUrl(rule))
+	filtered := router.Route(invokers, &cUrl, canary)
+	assert.Equal(t, 2, len(filtered))
+
+	stable := invocation.NewRPCInvocationWithOptions(invocation.WithAttachments(map[string]interface{}{"tag": "stable"}))
+	filtered = router.Route(invokers, &cUrl, stable)
+	assert.Equal(t, invokers, filtered)
+}
+
+func TestRouteScopeWhen(t *testing.T) {
+	inv := &invocation.RPCInvocation{}
+	cUrl, _ := common.NewURL("consumer://1.1.1.1/com.foo.BarService?weight=75")
+
+	rule := base64.URLEncoding.EncodeToString([]byte("weight = 50~100 => region = us-east"))
+	router, _ := newConditionRouterFactory().NewPriorityRouter(getRouteUrl(rule))
+	assert.Equal(t, true, router.(*ConditionRouter).MatchWhen(&cUrl, inv))
+
+	// lower bound is inclusive
+	ruleLo := base64.URLEncoding.EncodeToString([]byte("weight = 75~100 => region = us-east"))
+	routerLo, _ := newConditionRouterFactory().NewPriorityRouter(getRouteUrl(ruleLo))
+	assert.Equal(t, true, routerLo.(*ConditionRouter).MatchWhen(&cUrl, inv))
+
+	// upper bound is inclusive
+	ruleHi := base64.URLEncoding.EncodeToString([]byte("weight = 50~75 => region = us-east"))
+	routerHi, _ := newConditionRouterFactory().NewPriorityRouter(getRouteUrl(ruleHi))
+	assert.Equal(t, true, routerHi.(*ConditionRouter).MatchWhen(&cUrl, inv))
+
+	// out of range
+	ruleOut := base64.URLEncoding.EncodeToString([]byte("weight = 80~100 => region = us-east"))
+	routerOut, _ := newConditionRouterFactory().NewPriorityRouter(getRouteUrl(ruleOut))
+	assert.Equal(t, false, routerOut.(*ConditionRouter).MatchWhen(&cUrl, inv))
+
+	// negative bounds
+	negUrl, _ := common.NewURL("consumer://1.1.1.1/com.foo.BarService?weight=-5")
+	ruleNeg := base64.URLEncoding.EncodeToString([]byte("weight = -10~-1 => region = us-east"))
+	routerNeg, _ := newConditionRouterFactory().NewPriorityRouter(getRouteUrl(ruleNeg))
+	assert.Equal(t, true, routerNeg.(*ConditionRouter).MatchWhen(&negUrl, inv))
+
+	// a malformed range falls through to the wildcard matcher, which
+	// compares the pattern and sample as plain strings
+	ruleMalformed := base64.URLEncoding.EncodeToString([]byte("weight = 50~ => region = us-east"))
+	routerMalformed, _ := newConditionRouterFactory().NewPriorityRouter(getRouteUrl(ruleMalformed))
+	assert.Equal(t, false, routerMalformed.(*ConditionRouter).MatchWhen(&cUrl, inv))
+}
+
+func TestRouteScopeMethodsAndArguments(t *testing.T) {
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("getFoo"), invocation.WithParameterTypes([]reflect.Type{}), invocation.WithArguments([]interface{}{}))
+	cUrl, _ := common.NewURL("consumer://1.1.1.1/com.foo.BarService?methods=getFoo&arguments[0]=1")
+
+	rule := base64.URLEncoding.EncodeToString([]byte("methods = getFoo & arguments[0] = 1~100 => host = 10.0.0.0/8"))
+	router, _ := newConditionRouterFactory().NewPriorityRouter(getRouteUrl(rule))
+	assert.Equal(t, true, router.(*ConditionRouter).MatchWhen(&cUrl, inv))
+}
@@ -0,0 +1,56 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package matcher
+
+import (
+	"strings"
+)
+
+import (
+	"github.com/apache/dubbo-go/common"
+	"github.com/apache/dubbo-go/common/extension"
+	"github.com/apache/dubbo-go/protocol"
+)
+
+// attachmentConditionMatcherPrefix is the condition key prefix that routes a
+// key to the attachment matcher instead of the default URL param matcher,
+// e.g. "attachments.tag = canary => host = 10.0.0.0/8".
+const attachmentConditionMatcherPrefix = "attachments."
+
+func init() {
+	extension.SetConditionMatcherPrefixFactory(attachmentConditionMatcherPrefix, newAttachmentConditionMatcher)
+}
+
+// newAttachmentConditionMatcher builds a ConditionMatcher for a
+// "attachments.xxx" condition key. Its sample is the invocation attachment
+// named by the part of key after the prefix, so rules can route on
+// request-scoped metadata (e.g. a canary tag) instead of just URL params.
+func newAttachmentConditionMatcher(key string) extension.ConditionMatcher {
+	attachmentKey := strings.TrimPrefix(key, attachmentConditionMatcherPrefix)
+	return NewBaseConditionMatcher(key, func(_ *common.URL, inv protocol.Invocation) string {
+		if inv == nil {
+			return ""
+		}
+		if value, ok := inv.Attachment(attachmentKey); ok {
+			if s, ok := value.(string); ok {
+				return s
+			}
+		}
+		return inv.GetAttachmentWithDefaultValue(attachmentKey, "")
+	})
+}
@@ -0,0 +1,71 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package matcher provides the ConditionMatcher implementations that back
+// the condition router's extension points: one matcher per condition key
+// (host, methods, arbitrary URL params, invocation attachments, ...), each
+// delegating the actual pattern comparison to the extension.ValuePattern
+// registry.
+package matcher
+
+import (
+	"github.com/apache/dubbo-go/common"
+	"github.com/apache/dubbo-go/common/extension"
+	"github.com/apache/dubbo-go/protocol"
+)
+
+// ValueGetter extracts the sample value a ConditionMatcher compares against,
+// e.g. the host matcher's ValueGetter returns url.Ip.
+type ValueGetter func(url *common.URL, inv protocol.Invocation) string
+
+// BaseConditionMatcher is the common extension.ConditionMatcher
+// implementation: it fetches its sample via ValueGetter and asks the
+// registered extension.ValuePattern list, in priority order, to decide
+// whether the rule's pattern matches it. Every built-in condition key is a
+// BaseConditionMatcher configured with a different ValueGetter.
+type BaseConditionMatcher struct {
+	// Key is the condition key this matcher was built for, e.g. "host".
+	Key string
+	// ValueGetter reads this matcher's sample value from url/inv.
+	ValueGetter ValueGetter
+}
+
+// NewBaseConditionMatcher returns a BaseConditionMatcher for key that reads
+// its sample value via getter.
+func NewBaseConditionMatcher(key string, getter ValueGetter) *BaseConditionMatcher {
+	return &BaseConditionMatcher{Key: key, ValueGetter: getter}
+}
+
+// GetValue implements extension.ConditionMatcher.
+func (m *BaseConditionMatcher) GetValue(url *common.URL, inv protocol.Invocation) string {
+	return m.ValueGetter(url, inv)
+}
+
+// IsMatch implements extension.ConditionMatcher. It delegates to the first
+// registered extension.ValuePattern whose ShouldMatch accepts value.
+func (m *BaseConditionMatcher) IsMatch(value string, url *common.URL, inv protocol.Invocation, isWhenCondition bool) bool {
+	sample := m.GetValue(url, inv)
+	if len(sample) == 0 {
+		return false
+	}
+	for _, pattern := range extension.GetValuePatterns() {
+		if pattern.ShouldMatch(value) {
+			return pattern.Match(value, sample, url, inv, isWhenCondition)
+		}
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package matcher
+
+import (
+	"github.com/apache/dubbo-go/common"
+	"github.com/apache/dubbo-go/common/extension"
+	"github.com/apache/dubbo-go/protocol"
+)
+
+func init() {
+	extension.SetConditionMatcherFactory("methods", newMethodConditionMatcher)
+}
+
+// newMethodConditionMatcher builds the "methods" matcher. Its sample is the
+// invocation's method name when one is being routed, falling back to the
+// comma separated "methods" param advertised by url so that rules can still
+// be evaluated against a bare URL (no Invocation available).
+func newMethodConditionMatcher(key string) extension.ConditionMatcher {
+	return NewBaseConditionMatcher(key, func(url *common.URL, inv protocol.Invocation) string {
+		if inv != nil && len(inv.MethodName()) > 0 {
+			return inv.MethodName()
+		}
+		return url.GetParam(key, "")
+	})
+}
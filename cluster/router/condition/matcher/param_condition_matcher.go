@@ -0,0 +1,43 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package matcher
+
+import (
+	"github.com/apache/dubbo-go/common"
+	"github.com/apache/dubbo-go/common/constant"
+	"github.com/apache/dubbo-go/common/extension"
+	"github.com/apache/dubbo-go/protocol"
+)
+
+func init() {
+	extension.SetDefaultConditionMatcherFactory(newParamConditionMatcher)
+}
+
+// newParamConditionMatcher builds the fallback matcher used for any
+// condition key that has no dedicated ConditionMatcher of its own, e.g.
+// "serialization" or "weight". Its sample is the URL param of the same
+// name, falling back to "default.<key>" so a provider-side default can
+// still satisfy a rule written against the plain key.
+func newParamConditionMatcher(key string) extension.ConditionMatcher {
+	return NewBaseConditionMatcher(key, func(url *common.URL, _ protocol.Invocation) string {
+		if value := url.GetParam(key, ""); len(value) > 0 {
+			return value
+		}
+		return url.GetParam(constant.DefaultKey+"."+key, "")
+	})
+}
@@ -0,0 +1,113 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pattern_value
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+)
+
+import (
+	"github.com/apache/dubbo-go/common"
+	"github.com/apache/dubbo-go/common/extension"
+	"github.com/apache/dubbo-go/protocol"
+)
+
+// scopeValuePatternPriority is higher than wildcard's so a pattern like
+// "50~100" or a bare "50" is read as a numeric range/value before wildcard
+// ever gets a chance to treat it as a literal string.
+const scopeValuePatternPriority = 100
+
+// ScopeValuePattern matches a numeric range "lo~hi" (inclusive on both ends)
+// or a bare integer, e.g. "weight = 50~100" or "arguments[0] = 1~100".
+type ScopeValuePattern struct {
+	mu     sync.Mutex
+	bounds map[string][2]int64
+}
+
+func init() {
+	extension.SetValuePattern(&ScopeValuePattern{bounds: make(map[string][2]int64)})
+}
+
+// Priority implements extension.ValuePattern.
+func (p *ScopeValuePattern) Priority() int {
+	return scopeValuePatternPriority
+}
+
+// ShouldMatch implements extension.ValuePattern: pattern is a bare integer,
+// or exactly one "~" with an integer on each side and the lower bound not
+// above the upper one.
+func (p *ScopeValuePattern) ShouldMatch(pattern string) bool {
+	_, _, ok := p.parseBounds(pattern)
+	return ok
+}
+
+// Match implements extension.ValuePattern.
+func (p *ScopeValuePattern) Match(pattern, sample string, _ *common.URL, _ protocol.Invocation, _ bool) bool {
+	lo, hi, ok := p.parseBounds(pattern)
+	if !ok {
+		return false
+	}
+	value, err := strconv.ParseInt(sample, 10, 64)
+	if err != nil {
+		return false
+	}
+	return value >= lo && value <= hi
+}
+
+// parseBounds parses pattern into its inclusive [lo, hi] bounds. Successful
+// parses are cached, so a valid pattern re-used across many invokers is only
+// parsed once; a pattern that fails to parse is not cached and is parsed
+// again on every call.
+func (p *ScopeValuePattern) parseBounds(pattern string) (int64, int64, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if bounds, ok := p.bounds[pattern]; ok {
+		return bounds[0], bounds[1], true
+	}
+
+	var lo, hi int64
+	switch parts := strings.Split(pattern, "~"); len(parts) {
+	case 1:
+		v, err := strconv.ParseInt(strings.TrimSpace(parts[0]), 10, 64)
+		if err != nil {
+			return 0, 0, false
+		}
+		lo, hi = v, v
+	case 2:
+		l, err := strconv.ParseInt(strings.TrimSpace(parts[0]), 10, 64)
+		if err != nil {
+			return 0, 0, false
+		}
+		h, err := strconv.ParseInt(strings.TrimSpace(parts[1]), 10, 64)
+		if err != nil {
+			return 0, 0, false
+		}
+		if l > h {
+			return 0, 0, false
+		}
+		lo, hi = l, h
+	default:
+		return 0, 0, false
+	}
+
+	p.bounds[pattern] = [2]int64{lo, hi}
+	return lo, hi, true
+}
@@ -0,0 +1,79 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package pattern_value holds the extension.ValuePattern implementations
+// tried, in priority order, by matcher.BaseConditionMatcher.
+package pattern_value
+
+import (
+	"strings"
+)
+
+import (
+	"github.com/apache/dubbo-go/common"
+	"github.com/apache/dubbo-go/common/extension"
+	"github.com/apache/dubbo-go/protocol"
+)
+
+// wildcardValuePatternPriority is deliberately the lowest of the built-in
+// patterns: wildcard.ShouldMatch always returns true, so it must only run
+// once every more specific pattern (scope, regex, ...) has had a chance to
+// claim the pattern string first.
+const wildcardValuePatternPriority = 0
+
+// WildcardValuePattern is the original condition router matching behaviour:
+// plain equality, or a single trailing/leading/middle "*" wildcard. "$key"
+// placeholders are not handled here: the condition router resolves them
+// before a pattern reaches any ValuePattern.
+type WildcardValuePattern struct{}
+
+func init() {
+	extension.SetValuePattern(&WildcardValuePattern{})
+}
+
+// Priority implements extension.ValuePattern.
+func (p *WildcardValuePattern) Priority() int {
+	return wildcardValuePatternPriority
+}
+
+// ShouldMatch implements extension.ValuePattern. Wildcard is the catch-all:
+// it accepts anything no higher priority pattern has already claimed.
+func (p *WildcardValuePattern) ShouldMatch(_ string) bool {
+	return true
+}
+
+// Match implements extension.ValuePattern.
+func (p *WildcardValuePattern) Match(pattern, sample string, _ *common.URL, _ protocol.Invocation, _ bool) bool {
+	if pattern == "" || sample == "" {
+		return false
+	}
+
+	index := strings.Index(pattern, "*")
+	if index == -1 {
+		return sample == pattern
+	}
+
+	switch index {
+	case len(pattern) - 1:
+		return strings.HasPrefix(sample, pattern[:index])
+	case 0:
+		return strings.HasSuffix(sample, pattern[index+1:])
+	default:
+		parts := strings.SplitN(pattern, "*", 2)
+		return strings.HasPrefix(sample, parts[0]) && strings.HasSuffix(sample, parts[1])
+	}
+}
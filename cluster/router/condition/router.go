@@ -0,0 +1,275 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package condition implements a condition router: a static "when ... =>
+// then ..." rule, base64 encoded in the route URL's "rule" param, that
+// filters invokers for a consumer. Matching a single key/pattern pair is
+// delegated to the extension.ConditionMatcher registered for that key
+// (see cluster/router/condition/matcher), so new condition keys can be
+// added without changing this file.
+package condition
+
+import (
+	"encoding/base64"
+	"strings"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"github.com/apache/dubbo-go/common"
+	"github.com/apache/dubbo-go/common/extension"
+	"github.com/apache/dubbo-go/common/logger"
+	"github.com/apache/dubbo-go/protocol"
+)
+
+// MatchPair is the parsed "key = v1,v2 & key != v3" clause for a single
+// condition key: the set of patterns that must include a match, and the set
+// of patterns that must not.
+type MatchPair struct {
+	Matches    map[string]struct{}
+	Mismatches map[string]struct{}
+}
+
+// ConditionRouter is a static condition router built from a single
+// "when ... => then ..." rule.
+type ConditionRouter struct {
+	url      *common.URL
+	priority int64
+	Force    bool
+
+	whenCondition map[string]MatchPair
+	whenAlways    *bool
+
+	thenCondition map[string]MatchPair
+	thenAlways    *bool
+}
+
+// NewConditionRouter builds a ConditionRouter from url's "rule" param (a
+// base64 encoded condition rule) and its "force"/"priority" params.
+func NewConditionRouter(url *common.URL) (*ConditionRouter, error) {
+	if url == nil {
+		return nil, perrors.Errorf("illegal route URL: nil")
+	}
+	ruleParam := url.GetParam("rule", "")
+	if len(ruleParam) == 0 {
+		return nil, perrors.Errorf("illegal route rule: %s, rule can not be empty", url.String())
+	}
+	decoded, err := base64.URLEncoding.DecodeString(ruleParam)
+	if err != nil {
+		return nil, perrors.WithMessagef(err, "decode condition rule %s", ruleParam)
+	}
+	whenPart, thenPart := splitRule(string(decoded))
+
+	whenCondition, whenAlways := parseRule(whenPart, true)
+	thenCondition, thenAlways := parseRule(thenPart, false)
+
+	return &ConditionRouter{
+		url:           url,
+		priority:      url.GetParamInt64("priority", 0),
+		Force:         url.GetParamBool("force", false),
+		whenCondition: whenCondition,
+		whenAlways:    whenAlways,
+		thenCondition: thenCondition,
+		thenAlways:    thenAlways,
+	}, nil
+}
+
+// splitRule splits a "when => then" rule into its two halves. A rule with no
+// "=>" is all when-part with an empty then-part, which parseRule reads as
+// matching no invoker.
+func splitRule(rule string) (when string, then string) {
+	parts := strings.SplitN(rule, "=>", 2)
+	when = strings.TrimSpace(parts[0])
+	if len(parts) > 1 {
+		then = strings.TrimSpace(parts[1])
+	}
+	return
+}
+
+// parseRule parses one half of a condition rule into its per-key MatchPairs.
+// An empty half is treated as emptyMeans (conditions always hold on the
+// "when" side, never hold on the "then" side); the literals "true"/"false"
+// always override that default.
+func parseRule(rule string, emptyMeans bool) (map[string]MatchPair, *bool) {
+	switch {
+	case len(rule) == 0:
+		always := emptyMeans
+		return nil, &always
+	case strings.EqualFold(rule, "true"):
+		always := true
+		return nil, &always
+	case strings.EqualFold(rule, "false"):
+		always := false
+		return nil, &always
+	}
+
+	pairs := make(map[string]MatchPair)
+	for _, clause := range strings.Split(rule, "&") {
+		clause = strings.TrimSpace(clause)
+		if len(clause) == 0 {
+			continue
+		}
+
+		var key, operator, value string
+		switch {
+		case strings.Contains(clause, "!="):
+			idx := strings.Index(clause, "!=")
+			key, value, operator = strings.TrimSpace(clause[:idx]), strings.TrimSpace(clause[idx+2:]), "!="
+		case strings.Contains(clause, "="):
+			idx := strings.Index(clause, "=")
+			key, value, operator = strings.TrimSpace(clause[:idx]), strings.TrimSpace(clause[idx+1:]), "="
+		default:
+			continue
+		}
+
+		pair, ok := pairs[key]
+		if !ok {
+			pair = MatchPair{Matches: make(map[string]struct{}), Mismatches: make(map[string]struct{})}
+		}
+		for _, v := range strings.Split(value, ",") {
+			v = strings.TrimSpace(v)
+			if len(v) == 0 {
+				continue
+			}
+			if operator == "!=" {
+				pair.Mismatches[v] = struct{}{}
+			} else {
+				pair.Matches[v] = struct{}{}
+			}
+		}
+		pairs[key] = pair
+	}
+	return pairs, nil
+}
+
+// MatchWhen reports whether this rule's when-condition applies to url/inv,
+// i.e. whether the rule is relevant to this request at all.
+func (c *ConditionRouter) MatchWhen(url *common.URL, invocation protocol.Invocation) bool {
+	if c.whenAlways != nil {
+		return *c.whenAlways
+	}
+	return matchCondition(c.whenCondition, url, url, invocation, true)
+}
+
+// matchThen reports whether invokerURL satisfies this rule's then-condition,
+// with consumerURL available for $placeholder resolution.
+func (c *ConditionRouter) matchThen(invokerURL, consumerURL *common.URL, invocation protocol.Invocation) bool {
+	if c.thenAlways != nil {
+		return *c.thenAlways
+	}
+	return matchCondition(c.thenCondition, invokerURL, consumerURL, invocation, false)
+}
+
+// Route filters invokers against this rule. If the when-condition does not
+// match, the rule does not apply and every invoker passes through unchanged.
+// Otherwise only invokers satisfying the then-condition are kept; if none
+// do, the rule falls back to returning every invoker unless Force is set.
+func (c *ConditionRouter) Route(invokers []protocol.Invoker, url *common.URL, invocation protocol.Invocation) []protocol.Invoker {
+	if len(invokers) == 0 {
+		return invokers
+	}
+	if !c.MatchWhen(url, invocation) {
+		return invokers
+	}
+
+	result := make([]protocol.Invoker, 0, len(invokers))
+	for _, invoker := range invokers {
+		invokerURL := invoker.GetUrl()
+		if c.matchThen(&invokerURL, url, invocation) {
+			result = append(result, invoker)
+		}
+	}
+	if len(result) > 0 {
+		return result
+	}
+	if c.Force {
+		logger.Warnf("the current consumer in the service %v uses the condition rule but no invoker matches, and force=true, so an empty invoker list is returned", url.ServiceKey())
+		return result
+	}
+	return invokers
+}
+
+// URL returns the route URL this router was built from.
+func (c *ConditionRouter) URL() common.URL {
+	return *c.url
+}
+
+// Priority returns the rule's priority, used to order multiple routers
+// against the same invokers.
+func (c *ConditionRouter) Priority() int64 {
+	return c.priority
+}
+
+// matchCondition evaluates pairs against the sample url/inv, resolving
+// $placeholders against param (the consumer URL).
+func matchCondition(pairs map[string]MatchPair, url, param *common.URL, invocation protocol.Invocation, isWhenCondition bool) bool {
+	for key, pair := range pairs {
+		factory := extension.GetConditionMatcherFactory(key)
+		if factory == nil {
+			return false
+		}
+		m := factory(key)
+
+		if len(pair.Mismatches) > 0 {
+			mismatched := false
+			for pattern := range pair.Mismatches {
+				if m.IsMatch(resolvePlaceholder(pattern, param, invocation), url, invocation, isWhenCondition) {
+					mismatched = true
+					break
+				}
+			}
+			if mismatched {
+				return false
+			}
+			if len(pair.Matches) == 0 {
+				continue
+			}
+		}
+
+		if len(pair.Matches) > 0 {
+			matched := false
+			for pattern := range pair.Matches {
+				if m.IsMatch(resolvePlaceholder(pattern, param, invocation), url, invocation, isWhenCondition) {
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// resolvePlaceholder replaces a "$otherKey" pattern with the value otherKey's
+// matcher reads from param, so a then-side rule like "host = $host" can
+// refer back to the consumer's own host.
+func resolvePlaceholder(pattern string, param *common.URL, invocation protocol.Invocation) string {
+	if !strings.HasPrefix(pattern, "$") || param == nil {
+		return pattern
+	}
+	placeholderKey := pattern[1:]
+	factory := extension.GetConditionMatcherFactory(placeholderKey)
+	if factory == nil {
+		return pattern
+	}
+	return factory(placeholderKey).GetValue(param, invocation)
+}
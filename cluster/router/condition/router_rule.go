@@ -0,0 +1,81 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package condition
+
+import (
+	"encoding/base64"
+	"strconv"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+import (
+	"github.com/apache/dubbo-go/common"
+)
+
+// RouterRule is the YAML document a config center "{key}.condition-router"
+// entry is expected to contain: a list of "when => then" condition rules
+// that share a scope, a force/enabled flag and a priority.
+type RouterRule struct {
+	Scope      string   `yaml:"scope"`
+	Force      bool     `yaml:"force"`
+	Runtime    bool     `yaml:"runtime"`
+	Enabled    bool     `yaml:"enabled"`
+	Priority   int64    `yaml:"priority"`
+	Key        string   `yaml:"key"`
+	Conditions []string `yaml:"conditions"`
+}
+
+// parseRouterRule unmarshals rawRule, defaulting Enabled to true so a
+// document that omits the field behaves as if it were present.
+func parseRouterRule(rawRule string) (*RouterRule, error) {
+	rule := &RouterRule{Enabled: true}
+	if err := yaml.Unmarshal([]byte(rawRule), rule); err != nil {
+		return nil, perrors.WithMessagef(err, "parse condition-router rule: %s", rawRule)
+	}
+	return rule, nil
+}
+
+// toConditionRouters builds one ConditionRouter per entry in Conditions,
+// each inheriting this document's Force and Priority. A disabled rule
+// yields no routers at all.
+func (r *RouterRule) toConditionRouters() ([]*ConditionRouter, error) {
+	if !r.Enabled {
+		return nil, nil
+	}
+	routers := make([]*ConditionRouter, 0, len(r.Conditions))
+	for _, condition := range r.Conditions {
+		ruleURL, err := common.NewURL("condition://0.0.0.0/")
+		if err != nil {
+			return nil, perrors.WithMessage(err, "build condition-router rule URL")
+		}
+		ruleURL.AddParam("rule", base64.URLEncoding.EncodeToString([]byte(condition)))
+		ruleURL.AddParam("force", strconv.FormatBool(r.Force))
+		ruleURL.AddParam("priority", strconv.FormatInt(r.Priority, 10))
+
+		conditionRouter, err := NewConditionRouter(&ruleURL)
+		if err != nil {
+			return nil, perrors.WithMessagef(err, "build condition router for rule: %s", condition)
+		}
+		routers = append(routers, conditionRouter)
+	}
+	return routers, nil
+}
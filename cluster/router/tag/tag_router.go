@@ -0,0 +1,223 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package tag implements the tag router: it isolates traffic into named
+// groups ("canary", "gray", ...) using either a static "dubbo.tag" provider
+// URL param or a dynamic, config-center-driven address-to-tag mapping, and
+// steers a request by the "dubbo.tag" invocation attachment the consumer
+// sets.
+package tag
+
+import (
+	"math"
+	"strings"
+	"sync"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"github.com/apache/dubbo-go/cluster/router"
+	"github.com/apache/dubbo-go/common"
+	"github.com/apache/dubbo-go/common/config_center"
+	"github.com/apache/dubbo-go/common/constant"
+	"github.com/apache/dubbo-go/common/extension"
+	"github.com/apache/dubbo-go/common/logger"
+	"github.com/apache/dubbo-go/protocol"
+)
+
+// tagKey is both the provider URL param and the invocation attachment the
+// tag router reads a request/provider's tag from.
+const tagKey = "dubbo.tag"
+
+// tagRouterRuleSuffix is appended to the application name to get the
+// config center key a TagRouter subscribes to.
+const tagRouterRuleSuffix = ".tag-router"
+
+func init() {
+	extension.SetRouterFactory(constant.TagRouterName, newTagRouterFactory)
+}
+
+// TagRouter isolates invokers into named tags. A request carrying a non-
+// empty "dubbo.tag" attachment is restricted to invokers tagged the same
+// way (falling back to untagged invokers, or to nothing at all if the rule
+// is forced); a request with no tag is restricted to untagged invokers, so
+// default traffic never leaks into a tagged group.
+type TagRouter struct {
+	mu             sync.RWMutex
+	url            *common.URL
+	application    string
+	dynamicConfig  config_center.DynamicConfiguration
+	tagToAddresses map[string][]string
+	force          bool
+	enabled        bool
+}
+
+type tagRouterFactory struct{}
+
+func newTagRouterFactory() router.PriorityRouterFactory {
+	return &tagRouterFactory{}
+}
+
+// NewPriorityRouter implements router.PriorityRouterFactory.
+func (f *tagRouterFactory) NewPriorityRouter(url *common.URL) (router.PriorityRouter, error) {
+	return NewTagRouter(url)
+}
+
+// NewTagRouter builds a TagRouter for url. If url names an application and
+// a config center is available, the router also subscribes to that
+// application's dynamic tag-router rule; otherwise it matches statically on
+// provider URLs' "dubbo.tag" param only.
+func NewTagRouter(url *common.URL) (*TagRouter, error) {
+	application := url.GetParam(constant.ApplicationKey, "")
+	if len(application) == 0 {
+		return &TagRouter{url: url, enabled: true}, nil
+	}
+	dc, err := extension.GetDefaultConfigCenter()
+	if err != nil {
+		return &TagRouter{url: url, application: application, enabled: true}, nil
+	}
+	return NewTagRouterWithConfig(url, dc)
+}
+
+// NewTagRouterWithConfig is NewTagRouter with an explicit
+// config_center.DynamicConfiguration, so callers (and tests) can drive it
+// against something other than the process-wide default.
+func NewTagRouterWithConfig(url *common.URL, dc config_center.DynamicConfiguration) (*TagRouter, error) {
+	if url == nil {
+		return nil, perrors.Errorf("illegal route URL: nil")
+	}
+	t := &TagRouter{
+		url:           url,
+		application:   url.GetParam(constant.ApplicationKey, ""),
+		dynamicConfig: dc,
+		enabled:       true,
+	}
+
+	key := t.application + tagRouterRuleSuffix
+	if err := dc.AddListener(key, t); err != nil {
+		return nil, perrors.WithMessagef(err, "listen tag-router key %s", key)
+	}
+	if rule, err := dc.GetRule(key); err == nil {
+		t.updateRule(rule)
+	}
+	return t, nil
+}
+
+// Process implements config_center.ConfigurationListener: it hot-swaps the
+// dynamic address-to-tag mapping whenever this application's tag-router key
+// changes, without restarting the process.
+func (t *TagRouter) Process(event *config_center.ConfigChangeEvent) {
+	value, _ := event.Value.(string)
+	t.updateRule(value)
+}
+
+func (t *TagRouter) updateRule(rawRule string) {
+	if len(strings.TrimSpace(rawRule)) == 0 {
+		t.mu.Lock()
+		t.tagToAddresses, t.force, t.enabled = nil, false, true
+		t.mu.Unlock()
+		return
+	}
+
+	rule, err := parseTagRouterRule(rawRule)
+	if err != nil {
+		logger.Errorf("tag router: parse rule for application %s failed: %v", t.application, err)
+		return
+	}
+
+	tagToAddresses := make(map[string][]string, len(rule.Tags))
+	for _, tag := range rule.Tags {
+		tagToAddresses[tag.Name] = tag.Addresses
+	}
+
+	t.mu.Lock()
+	t.tagToAddresses, t.force, t.enabled = tagToAddresses, rule.Force, rule.Enabled
+	t.mu.Unlock()
+}
+
+// tagOf returns the tag invokerURL belongs to: the dynamic rule's address
+// mapping takes precedence over the provider's own static "dubbo.tag" param,
+// so an operator can regroup running providers without redeploying them.
+func (t *TagRouter) tagOf(invokerURL common.URL) string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	for name, addresses := range t.tagToAddresses {
+		for _, address := range addresses {
+			if address == invokerURL.Location {
+				return name
+			}
+		}
+	}
+	return invokerURL.GetParam(tagKey, "")
+}
+
+// Route implements router.Router.
+func (t *TagRouter) Route(invokers []protocol.Invoker, _ *common.URL, invocation protocol.Invocation) []protocol.Invoker {
+	t.mu.RLock()
+	enabled, force := t.enabled, t.force
+	t.mu.RUnlock()
+	if !enabled || len(invokers) == 0 {
+		return invokers
+	}
+
+	requestTag := ""
+	if invocation != nil {
+		requestTag = invocation.GetAttachmentWithDefaultValue(tagKey, "")
+	}
+
+	if len(requestTag) > 0 {
+		tagged := make([]protocol.Invoker, 0, len(invokers))
+		for _, invoker := range invokers {
+			if t.tagOf(invoker.GetUrl()) == requestTag {
+				tagged = append(tagged, invoker)
+			}
+		}
+		if len(tagged) > 0 {
+			return tagged
+		}
+		if force {
+			// hard isolation: a forced tag must never spill onto untagged
+			// invokers.
+			return tagged
+		}
+	}
+
+	// no request tag, or a soft miss falling back: keep only invokers that
+	// do not belong to any tag, so default traffic never leaks into a
+	// tagged group.
+	untagged := make([]protocol.Invoker, 0, len(invokers))
+	for _, invoker := range invokers {
+		if len(t.tagOf(invoker.GetUrl())) == 0 {
+			untagged = append(untagged, invoker)
+		}
+	}
+	return untagged
+}
+
+// URL implements router.Router.
+func (t *TagRouter) URL() common.URL {
+	return *t.url
+}
+
+// Priority implements router.Router. The tag router always runs first, so
+// it is given the lowest possible priority value.
+func (t *TagRouter) Priority() int64 {
+	return math.MinInt64
+}
@@ -0,0 +1,49 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tag
+
+import (
+	perrors "github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// tagRouterRuleTag is one "tags" entry of a tag-router YAML rule: a name and
+// the set of provider addresses (ip:port) grouped under it.
+type tagRouterRuleTag struct {
+	Name      string   `yaml:"name"`
+	Addresses []string `yaml:"addresses"`
+}
+
+// tagRouterRule is the YAML document a config center
+// "{application}.tag-router" entry is expected to contain.
+type tagRouterRule struct {
+	Force   bool               `yaml:"force"`
+	Runtime bool               `yaml:"runtime"`
+	Enabled bool               `yaml:"enabled"`
+	Tags    []tagRouterRuleTag `yaml:"tags"`
+}
+
+// parseTagRouterRule unmarshals rawRule, defaulting Enabled to true so a
+// document that omits the field behaves as if it were present.
+func parseTagRouterRule(rawRule string) (*tagRouterRule, error) {
+	rule := &tagRouterRule{Enabled: true}
+	if err := yaml.Unmarshal([]byte(rawRule), rule); err != nil {
+		return nil, perrors.WithMessagef(err, "parse tag-router rule: %s", rawRule)
+	}
+	return rule, nil
+}
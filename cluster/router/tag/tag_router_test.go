@@ -0,0 +1,160 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tag
+
+import (
+	"context"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"github.com/apache/dubbo-go/common"
+	"github.com/apache/dubbo-go/common/config_center"
+	"github.com/apache/dubbo-go/common/logger"
+	"github.com/apache/dubbo-go/protocol"
+	"github.com/apache/dubbo-go/protocol/invocation"
+)
+
+type mockInvoker struct {
+	url common.URL
+}
+
+func newMockInvoker(url common.URL) *mockInvoker {
+	return &mockInvoker{url: url}
+}
+
+func (m *mockInvoker) GetUrl() common.URL { return m.url }
+func (m *mockInvoker) IsAvailable() bool  { return true }
+func (m *mockInvoker) IsDestroyed() bool  { return false }
+func (m *mockInvoker) Destroy()           { logger.Infof("destroy invoker: %v", m.url.String()) }
+func (m *mockInvoker) Invoke(_ context.Context, _ protocol.Invocation) protocol.Result {
+	return &protocol.RPCResult{}
+}
+
+func invocationWithTag(tag string) protocol.Invocation {
+	if len(tag) == 0 {
+		return &invocation.RPCInvocation{}
+	}
+	return invocation.NewRPCInvocationWithOptions(invocation.WithAttachments(map[string]interface{}{tagKey: tag}))
+}
+
+func newInvokers() (canary, stable, untagged protocol.Invoker) {
+	canaryURL, _ := common.NewURL("dubbo://10.20.3.1:20880/com.foo.BarService?dubbo.tag=canary")
+	stableURL, _ := common.NewURL("dubbo://10.20.3.2:20880/com.foo.BarService?dubbo.tag=stable")
+	untaggedURL, _ := common.NewURL("dubbo://10.20.3.3:20880/com.foo.BarService")
+	return newMockInvoker(canaryURL), newMockInvoker(stableURL), newMockInvoker(untaggedURL)
+}
+
+func TestTagRouteMatch(t *testing.T) {
+	canary, stable, untagged := newInvokers()
+	invokers := []protocol.Invoker{canary, stable, untagged}
+	router, err := NewTagRouter(&common.URL{})
+	assert.NoError(t, err)
+
+	filtered := router.Route(invokers, nil, invocationWithTag("canary"))
+	assert.Equal(t, []protocol.Invoker{canary}, filtered)
+}
+
+func TestTagRouteMissWithoutForceFallsBackToUntagged(t *testing.T) {
+	_, stable, untagged := newInvokers()
+	invokers := []protocol.Invoker{stable, untagged}
+	router, err := NewTagRouter(&common.URL{})
+	assert.NoError(t, err)
+
+	filtered := router.Route(invokers, nil, invocationWithTag("canary"))
+	assert.Equal(t, []protocol.Invoker{untagged}, filtered)
+}
+
+func TestTagRouteMissWithForceIsHardIsolation(t *testing.T) {
+	_, stable, untagged := newInvokers()
+	invokers := []protocol.Invoker{stable, untagged}
+	router, err := NewTagRouter(&common.URL{})
+	assert.NoError(t, err)
+	router.force = true
+
+	filtered := router.Route(invokers, nil, invocationWithTag("canary"))
+	assert.Equal(t, 0, len(filtered))
+}
+
+func TestTagRouteNoTagExcludesTaggedInvokers(t *testing.T) {
+	canary, stable, untagged := newInvokers()
+	invokers := []protocol.Invoker{canary, stable, untagged}
+	router, err := NewTagRouter(&common.URL{})
+	assert.NoError(t, err)
+
+	filtered := router.Route(invokers, nil, invocationWithTag(""))
+	assert.Equal(t, []protocol.Invoker{untagged}, filtered)
+}
+
+// fakeDynamicConfiguration is a minimal config_center.DynamicConfiguration
+// that only implements what TagRouter needs.
+type fakeDynamicConfiguration struct {
+	config_center.DynamicConfiguration
+
+	rules     map[string]string
+	listeners map[string]config_center.ConfigurationListener
+}
+
+func newFakeDynamicConfiguration() *fakeDynamicConfiguration {
+	return &fakeDynamicConfiguration{
+		rules:     make(map[string]string),
+		listeners: make(map[string]config_center.ConfigurationListener),
+	}
+}
+
+func (f *fakeDynamicConfiguration) AddListener(key string, listener config_center.ConfigurationListener, _ ...config_center.Option) error {
+	f.listeners[key] = listener
+	return nil
+}
+
+func (f *fakeDynamicConfiguration) GetRule(key string, _ ...config_center.Option) (string, error) {
+	return f.rules[key], nil
+}
+
+func (f *fakeDynamicConfiguration) push(key, rule string) {
+	f.rules[key] = rule
+	if listener, ok := f.listeners[key]; ok {
+		listener.Process(&config_center.ConfigChangeEvent{Key: key, Value: rule})
+	}
+}
+
+func TestTagRouteDynamicRuleReload(t *testing.T) {
+	dc := newFakeDynamicConfiguration()
+	url, _ := common.NewURL("consumer://1.1.1.1/com.foo.BarService?application=bar-consumer")
+	router, err := NewTagRouterWithConfig(&url, dc)
+	assert.NoError(t, err)
+
+	untaggedURL, _ := common.NewURL("dubbo://10.20.3.9:20880/com.foo.BarService")
+	invoker := newMockInvoker(untaggedURL)
+	invokers := []protocol.Invoker{invoker}
+
+	// before any rule is pushed, the address is untagged: a canary request
+	// falls back to it
+	assert.Equal(t, invokers, router.Route(invokers, nil, invocationWithTag("canary")))
+
+	dc.push("bar-consumer.tag-router", "force: true\nenabled: true\ntags:\n  - name: canary\n    addresses:\n      - 10.20.3.9:20880\n")
+
+	// the same address is now tagged canary: a canary request matches it
+	// directly, and a plain request excludes it
+	assert.Equal(t, invokers, router.Route(invokers, nil, invocationWithTag("canary")))
+	assert.Equal(t, 0, len(router.Route(invokers, nil, invocationWithTag(""))))
+}
@@ -0,0 +1,89 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package extension
+
+import (
+	"strings"
+)
+
+import (
+	"github.com/apache/dubbo-go/common"
+	"github.com/apache/dubbo-go/protocol"
+)
+
+// ConditionMatcher matches the sample value it reads from url/inv for a
+// single condition key (e.g. "host", "methods", "attachments.tag") against
+// a single pattern string taken from one side of a condition rule. Which
+// ConditionMatcher handles a given key is resolved through
+// GetConditionMatcherFactory, so new condition keys can be added without
+// touching the condition router itself.
+type ConditionMatcher interface {
+	// IsMatch reports whether value, a single pattern taken from the "when"
+	// or "then" half of a condition rule, matches the sample this matcher
+	// reads from url/inv. isWhenCondition is true while evaluating the
+	// "when" half, so implementations that behave differently depending on
+	// which side of the rule they are evaluating (e.g. $placeholder
+	// resolution) can tell them apart.
+	IsMatch(value string, url *common.URL, inv protocol.Invocation, isWhenCondition bool) bool
+
+	// GetValue extracts the raw sample value this matcher reads from
+	// url/inv, e.g. the "host" matcher returns url.Ip.
+	GetValue(url *common.URL, inv protocol.Invocation) string
+}
+
+// ConditionMatcherFactory builds a ConditionMatcher bound to key.
+type ConditionMatcherFactory func(key string) ConditionMatcher
+
+var (
+	conditionMatcherFactories       = make(map[string]ConditionMatcherFactory)
+	conditionMatcherPrefixFactories = make(map[string]ConditionMatcherFactory)
+	defaultConditionMatcherFactory  ConditionMatcherFactory
+)
+
+// SetConditionMatcherFactory registers factory for an exact condition key,
+// e.g. "host" or "methods".
+func SetConditionMatcherFactory(key string, factory ConditionMatcherFactory) {
+	conditionMatcherFactories[key] = factory
+}
+
+// SetConditionMatcherPrefixFactory registers factory for every condition key
+// starting with prefix, e.g. "attachments." for attachment-based rules.
+func SetConditionMatcherPrefixFactory(prefix string, factory ConditionMatcherFactory) {
+	conditionMatcherPrefixFactories[prefix] = factory
+}
+
+// SetDefaultConditionMatcherFactory registers the factory used for any
+// condition key that has no exact or prefix match, i.e. plain URL params.
+func SetDefaultConditionMatcherFactory(factory ConditionMatcherFactory) {
+	defaultConditionMatcherFactory = factory
+}
+
+// GetConditionMatcherFactory resolves the factory responsible for key,
+// trying an exact match first, then the registered prefixes, and finally
+// falling back to the default (URL-param) matcher.
+func GetConditionMatcherFactory(key string) ConditionMatcherFactory {
+	if factory, ok := conditionMatcherFactories[key]; ok {
+		return factory
+	}
+	for prefix, factory := range conditionMatcherPrefixFactories {
+		if strings.HasPrefix(key, prefix) {
+			return factory
+		}
+	}
+	return defaultConditionMatcherFactory
+}
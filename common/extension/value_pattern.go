@@ -0,0 +1,68 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package extension
+
+import (
+	"sort"
+)
+
+import (
+	"github.com/apache/dubbo-go/common"
+	"github.com/apache/dubbo-go/protocol"
+)
+
+// ValuePattern recognizes one flavour of pattern syntax (wildcard, numeric
+// range, regex, ...) that a condition rule's pattern string can use, e.g.
+// the "10.20.3.*" in "host = 10.20.3.*" or the "50~100" in "weight = 50~100".
+// ConditionMatcher implementations ask every registered ValuePattern, highest
+// Priority first, whether it ShouldMatch the pattern, and defer to the first
+// one that says yes.
+type ValuePattern interface {
+	// Priority determines the order in which ValuePatterns are tried; higher
+	// values are tried first so more specific syntaxes can pre-empt the
+	// catch-all wildcard matcher.
+	Priority() int
+
+	// ShouldMatch reports whether this ValuePattern knows how to interpret
+	// pattern. The first ValuePattern, in priority order, to return true
+	// owns the match decision for that pattern.
+	ShouldMatch(pattern string) bool
+
+	// Match reports whether sample satisfies pattern. url and inv are the
+	// same values the owning ConditionMatcher received, passed through for
+	// implementations that need request context.
+	Match(pattern, sample string, url *common.URL, inv protocol.Invocation, isWhenCondition bool) bool
+}
+
+var valuePatterns []ValuePattern
+
+// SetValuePattern registers a ValuePattern. Registration order does not
+// matter: GetValuePatterns always returns them sorted by descending
+// Priority.
+func SetValuePattern(pattern ValuePattern) {
+	valuePatterns = append(valuePatterns, pattern)
+	sort.SliceStable(valuePatterns, func(i, j int) bool {
+		return valuePatterns[i].Priority() > valuePatterns[j].Priority()
+	})
+}
+
+// GetValuePatterns returns the registered ValuePatterns, highest Priority
+// first.
+func GetValuePatterns() []ValuePattern {
+	return valuePatterns
+}
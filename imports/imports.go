@@ -0,0 +1,27 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package imports blank-imports the built-in condition and tag router
+// packages so that importing this package alone is enough to register their
+// router factories via their init() functions. Callers that want these
+// routers available just import this package for its side effects.
+package imports
+
+import (
+	_ "github.com/apache/dubbo-go/cluster/router/condition"
+	_ "github.com/apache/dubbo-go/cluster/router/tag"
+)